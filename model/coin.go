@@ -0,0 +1,211 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// Decimals is the number of Coin units per 1 LNO (Coin is LNO's 10^-5 unit).
+const Decimals = 100000
+
+// linoFracDigits is the number of fractional digits a decimal LNO string may
+// carry, since Coin cannot represent anything finer than 10^-5 LNO.
+const linoFracDigits = 5
+
+var (
+	// ErrIllegalLino is returned when a string isn't a valid decimal number.
+	ErrIllegalLino = errors.New("illegal LNO string")
+	// ErrLinoPrecisionExceeded is returned when a LNO decimal string carries
+	// more than linoFracDigits fractional digits and the caller asked for
+	// exact conversion rather than truncation/rounding.
+	ErrLinoPrecisionExceeded = errors.New("LNO string has more than 5 fractional digits")
+	// ErrLinoOverflow is returned when the LNO amount doesn't fit in a Coin.
+	ErrLinoOverflow = errors.New("LNO overflow")
+	// ErrCoinOverflow is returned by arithmetic helpers that would produce a
+	// Coin outside the representable int64 range.
+	ErrCoinOverflow = errors.New("coin overflow")
+
+	maxCoinAmount = big.NewInt(math.MaxInt64)
+)
+
+// Int is an arbitrary-precision integer amount, in Coin units.
+type Int struct {
+	I *big.Int
+}
+
+// String implements fmt.Stringer.
+func (i Int) String() string {
+	return i.I.String()
+}
+
+// Coin is the smallest transferable unit on the Lino blockchain: 10^-5 LNO.
+type Coin struct {
+	Amount Int
+}
+
+// NewCoinFromInt64 returns a Coin of the given int64 amount.
+func NewCoinFromInt64(amount int64) Coin {
+	return Coin{Int{I: big.NewInt(amount)}}
+}
+
+// roundMode controls how LinoToCoin handles a fractional part longer than
+// linoFracDigits.
+type roundMode int
+
+const (
+	roundExact roundMode = iota
+	roundTruncate
+	roundUp
+)
+
+// LinoToCoin converts a decimal LNO string into its Coin amount. If lino
+// carries more than 5 fractional digits, it returns ErrLinoPrecisionExceeded
+// rather than silently rounding; use LinoToCoinTruncate or LinoToCoinRoundUp
+// if that's what the caller wants.
+func LinoToCoin(lino string) (Coin, error) {
+	return linoToCoin(lino, roundExact)
+}
+
+// MustLinoToCoin is like LinoToCoin but panics on error; for call sites
+// converting compile-time constant amounts.
+func MustLinoToCoin(lino string) Coin {
+	coin, err := LinoToCoin(lino)
+	if err != nil {
+		panic(err)
+	}
+	return coin
+}
+
+// LinoToCoinTruncate converts lino to Coin, dropping any fractional digits
+// beyond the 5 a Coin can represent instead of erroring.
+func LinoToCoinTruncate(lino string) (Coin, error) {
+	return linoToCoin(lino, roundTruncate)
+}
+
+// LinoToCoinRoundUp converts lino to Coin, rounding any nonzero fractional
+// digits beyond the 5 a Coin can represent away from zero to the next Coin
+// unit (so a negative amount becomes one Coin unit more negative).
+func LinoToCoinRoundUp(lino string) (Coin, error) {
+	return linoToCoin(lino, roundUp)
+}
+
+func linoToCoin(lino string, mode roundMode) (Coin, error) {
+	neg := false
+	if strings.HasPrefix(lino, "-") {
+		neg = true
+		lino = lino[1:]
+	}
+
+	intPart := lino
+	fracPart := ""
+	if idx := strings.IndexByte(lino, '.'); idx >= 0 {
+		intPart = lino[:idx]
+		fracPart = lino[idx+1:]
+	}
+
+	if intPart == "" {
+		intPart = "0"
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return NewCoinFromInt64(0), ErrIllegalLino
+	}
+
+	roundedUp := false
+	if len(fracPart) > linoFracDigits {
+		switch mode {
+		case roundExact:
+			return NewCoinFromInt64(0), ErrLinoPrecisionExceeded
+		case roundTruncate:
+			fracPart = fracPart[:linoFracDigits]
+		case roundUp:
+			roundedUp = strings.Trim(fracPart[linoFracDigits:], "0") != ""
+			fracPart = fracPart[:linoFracDigits]
+		}
+	}
+	fracPart = fracPart + strings.Repeat("0", linoFracDigits-len(fracPart))
+
+	amount, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return NewCoinFromInt64(0), ErrIllegalLino
+	}
+	if roundedUp {
+		amount.Add(amount, big.NewInt(1))
+	}
+	if amount.CmpAbs(maxCoinAmount) > 0 {
+		return NewCoinFromInt64(0), ErrLinoOverflow
+	}
+	if neg {
+		amount.Neg(amount)
+	}
+
+	return Coin{Int{I: amount}}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CoinToLNO formats c as a decimal LNO string, without an sdk.Dec round-trip.
+func (c Coin) CoinToLNO() string {
+	neg := c.Amount.I.Sign() < 0
+	digits := new(big.Int).Abs(c.Amount.I).String()
+
+	if len(digits) <= linoFracDigits {
+		digits = strings.Repeat("0", linoFracDigits-len(digits)+1) + digits
+	}
+
+	intPart := digits[:len(digits)-linoFracDigits]
+	fracPart := strings.TrimRight(digits[len(digits)-linoFracDigits:], "0")
+
+	out := intPart
+	if fracPart != "" {
+		out = intPart + "." + fracPart
+	}
+	if neg && out != "0" {
+		out = "-" + out
+	}
+	return out
+}
+
+// IsZero reports whether c is the zero Coin.
+func (c Coin) IsZero() bool {
+	return c.Amount.I.Sign() == 0
+}
+
+// Cmp returns -1, 0, or 1 depending on whether c is less than, equal to, or
+// greater than other.
+func (c Coin) Cmp(other Coin) int {
+	return c.Amount.I.Cmp(other.Amount.I)
+}
+
+// Add returns c+other, erroring with ErrCoinOverflow if the result would no
+// longer fit in an int64.
+func (c Coin) Add(other Coin) (Coin, error) {
+	sum := new(big.Int).Add(c.Amount.I, other.Amount.I)
+	if !sum.IsInt64() {
+		return NewCoinFromInt64(0), ErrCoinOverflow
+	}
+	return Coin{Int{I: sum}}, nil
+}
+
+// Sub returns c-other, erroring with ErrCoinOverflow if the result would no
+// longer fit in an int64.
+func (c Coin) Sub(other Coin) (Coin, error) {
+	diff := new(big.Int).Sub(c.Amount.I, other.Amount.I)
+	if !diff.IsInt64() {
+		return NewCoinFromInt64(0), ErrCoinOverflow
+	}
+	return Coin{Int{I: diff}}, nil
+}
+
+// String implements fmt.Stringer, printing the raw Coin amount (not LNO).
+func (c Coin) String() string {
+	return c.Amount.I.String()
+}
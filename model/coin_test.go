@@ -1,22 +1,10 @@
 package model
 
 import (
-	"errors"
-	"math"
+	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
-
-	sdk "github.com/cosmos/cosmos-sdk/types"
-)
-
-var (
-	// LowerBoundRat - the lower bound of Rat
-	LowerBoundRat = NewDecFromRat(1, Decimals)
-	// UpperBoundRat - the upper bound of Rat
-	UpperBoundRat = sdk.NewDec(math.MaxInt64 / Decimals)
-)
-
-const (
-	Decimals = 100000
 )
 
 func TestCoinToLNO(t *testing.T) {
@@ -79,33 +67,97 @@ func TestCoinToLNO(t *testing.T) {
 	}
 }
 
-//
-// helper function
-//
-
-// NewCoinFromInt64 - return int64 amount of Coin
-func NewCoinFromInt64(amount int64) Coin {
-	// return Coin{big.NewInt(amount)}
-	return Coin{Int{I: sdk.NewInt(amount).BigInt()}}
+func TestLinoToCoinPrecisionExceeded(t *testing.T) {
+	if _, err := LinoToCoin("1.123456"); err != ErrLinoPrecisionExceeded {
+		t.Errorf("expected ErrLinoPrecisionExceeded, got %v", err)
+	}
 }
 
-// LinoToCoin - convert 1 LNO to 10^5 Coin
-func LinoToCoin(lino string) (Coin, error) {
-	rat, err := sdk.NewDecFromStr(lino)
+func TestLinoToCoinTruncateAndRoundUp(t *testing.T) {
+	truncated, err := LinoToCoinTruncate("1.123456")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if truncated.Amount.String() != "112345" {
+		t.Errorf("truncate: got %v, want 112345", truncated.Amount.String())
+	}
+
+	roundedUp, err := LinoToCoinRoundUp("1.123451")
 	if err != nil {
-		return NewCoinFromInt64(0), errors.New("Illegal LNO")
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if roundedUp.Amount.String() != "112346" {
+		t.Errorf("round up: got %v, want 112346", roundedUp.Amount.String())
+	}
+}
+
+func TestCoinArithmetic(t *testing.T) {
+	a := NewCoinFromInt64(100)
+	b := NewCoinFromInt64(30)
+
+	sum, err := a.Add(b)
+	if err != nil || sum.Amount.String() != "130" {
+		t.Errorf("add: got %v, %v, want 130, nil", sum.Amount.String(), err)
+	}
+
+	diff, err := a.Sub(b)
+	if err != nil || diff.Amount.String() != "70" {
+		t.Errorf("sub: got %v, %v, want 70, nil", diff.Amount.String(), err)
 	}
-	if rat.GT(UpperBoundRat) {
-		return NewCoinFromInt64(0), errors.New("LNO overflow")
+
+	if a.Cmp(b) <= 0 {
+		t.Errorf("expected a > b")
+	}
+
+	if !NewCoinFromInt64(0).IsZero() {
+		t.Errorf("expected zero coin to be zero")
 	}
-	if rat.LT(LowerBoundRat) {
-		return NewCoinFromInt64(0), errors.New("LNO can't be less than lower bound")
+}
+
+// TestLinoToCoinRoundTrip fuzzes LinoToCoin/CoinToLNO over randomly
+// generated decimal strings with up to 5 fractional digits, which must
+// round-trip exactly since nothing is lost at that precision.
+func TestLinoToCoinRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		intPart := r.Int63n(1_000_000_000)
+		fracDigits := r.Intn(6)
+		fracPart := ""
+		for j := 0; j < fracDigits; j++ {
+			fracPart += strconv.Itoa(r.Intn(10))
+		}
+
+		lino := strconv.FormatInt(intPart, 10)
+		if fracPart != "" {
+			lino += "." + fracPart
+		}
+
+		coin, err := LinoToCoin(lino)
+		if err != nil {
+			t.Fatalf("LinoToCoin(%q): unexpected err %v", lino, err)
+		}
+
+		got := coin.CoinToLNO()
+		want := expectedLNO(lino)
+		if got != want {
+			t.Errorf("round-trip %q: got %q, want %q", lino, got, want)
+		}
 	}
-	return DecToCoin(rat.Mul(sdk.NewDec(Decimals))), nil
 }
 
-// DecToCoin - convert sdk.Dec to LNO coin
-// XXX(yumin): the unit of @p rat must be coin.
-func DecToCoin(rat sdk.Dec) Coin {
-	return Coin{Int{I: rat.RoundInt().BigInt()}}
+// expectedLNO computes the CoinToLNO-normalized form of a decimal LNO
+// string, trimming trailing zeros (and a dangling dot) only from the
+// fractional part, never from the integer part.
+func expectedLNO(lino string) string {
+	intPart, fracPart := lino, ""
+	if idx := strings.IndexByte(lino, '.'); idx >= 0 {
+		intPart, fracPart = lino[:idx], lino[idx+1:]
+	}
+
+	fracPart = strings.TrimRight(fracPart, "0")
+	if fracPart == "" {
+		return intPart
+	}
+	return intPart + "." + fracPart
 }
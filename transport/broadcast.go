@@ -0,0 +1,185 @@
+package transport
+
+import (
+	"context"
+	"sync"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+
+	"github.com/lino-network/lino-go/errors"
+	"github.com/lino-network/lino-go/model"
+)
+
+// BroadcastMode selects how long SignBuildBroadcastMode waits before
+// returning: fire-and-forget, mempool-accepted, or block-included.
+type BroadcastMode int
+
+const (
+	// BroadcastAsync returns as soon as the tx is submitted to the node,
+	// without waiting on mempool or block inclusion.
+	BroadcastAsync BroadcastMode = iota
+	// BroadcastSync returns once the node's mempool has checked the tx.
+	BroadcastSync
+	// BroadcastCommit returns once the tx is included in a block.
+	BroadcastCommit
+)
+
+// BroadcastResult is the unified result of a broadcast regardless of mode.
+// Ok means the tx was not rejected as far as the mode waited: for
+// BroadcastAsync it is set as soon as the node takes the submission (nothing
+// has been checked yet), for BroadcastSync once the mempool's CheckTx passes,
+// and for BroadcastCommit once both CheckTx and DeliverTx pass. Height is only
+// set by BroadcastCommit, once the tx has reached a block. RawError carries
+// the CheckTx/DeliverTx log on a rejection.
+type BroadcastResult struct {
+	TxHash   string
+	Height   int64
+	Ok       bool
+	RawError string
+}
+
+// BroadcastTxMode broadcasts tx using the given mode and normalizes the
+// three distinct Tendermint broadcast responses into a BroadcastResult.
+func (t Transport) BroadcastTxMode(ctx context.Context, tx []byte, mode BroadcastMode) (*BroadcastResult, error) {
+	node, err := t.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case BroadcastAsync:
+		res, err := node.BroadcastTxAsync(tx)
+		if err != nil {
+			return nil, err
+		}
+		return &BroadcastResult{TxHash: res.Hash.String(), Ok: true}, nil
+
+	case BroadcastSync:
+		res, err := node.BroadcastTxSync(tx)
+		if err != nil {
+			return nil, err
+		}
+		if res.Code != uint32(0) {
+			return &BroadcastResult{TxHash: res.Hash.String(), RawError: res.Log}, errors.QueryFail("broadcast rejected by mempool").AddBlockChainCode(res.Code).AddBlockChainLog(res.Log)
+		}
+		return &BroadcastResult{TxHash: res.Hash.String(), Ok: true}, nil
+
+	case BroadcastCommit:
+		res, err := t.BroadcastTxContext(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		return broadcastResultFromCommit(res), nil
+
+	default:
+		return nil, errors.InvalidArg("unknown broadcast mode")
+	}
+}
+
+func broadcastResultFromCommit(res *ctypes.ResultBroadcastTxCommit) *BroadcastResult {
+	if res.CheckTx.Code != uint32(0) {
+		return &BroadcastResult{TxHash: res.Hash.String(), RawError: res.CheckTx.Log}
+	}
+	if res.DeliverTx.Code != uint32(0) {
+		return &BroadcastResult{TxHash: res.Hash.String(), Height: res.Height, RawError: res.DeliverTx.Log}
+	}
+	return &BroadcastResult{TxHash: res.Hash.String(), Height: res.Height, Ok: true}
+}
+
+// SignBuildBroadcastMode signs msg with the given private key and broadcasts
+// it using mode, returning as soon as that mode is satisfied.
+func (t Transport) SignBuildBroadcastMode(ctx context.Context, msg model.Msg,
+	privKeyHex string, seq int64, memo string, mode BroadcastMode) (*BroadcastResult, error) {
+	msgs := []model.Msg{msg}
+
+	privKey, err := GetPrivKeyFromHex(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+
+	signMsgBytes, err := EncodeSignMsg(t.Cdc, msgs, t.chainId, seq)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := privKey.Sign(signMsgBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	txByte, err := EncodeTx(t.Cdc, msgs, privKey.PubKey(), sig, seq, memo)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.BroadcastTxMode(ctx, txByte, mode)
+}
+
+// SequenceManager caches the next account sequence per address so callers
+// can fire off many txs without manually tracking seq. It serializes
+// signing per address to avoid racing two in-flight txs onto the same seq.
+type SequenceManager struct {
+	transport *Transport
+
+	mu      sync.Mutex
+	locks   map[string]*sync.Mutex
+	nextSeq map[string]int64
+}
+
+// NewSequenceManager creates a SequenceManager backed by t.
+func NewSequenceManager(t *Transport) *SequenceManager {
+	return &SequenceManager{
+		transport: t,
+		locks:     make(map[string]*sync.Mutex),
+		nextSeq:   make(map[string]int64),
+	}
+}
+
+func (sm *SequenceManager) lockFor(address string) *sync.Mutex {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	l, ok := sm.locks[address]
+	if !ok {
+		l = &sync.Mutex{}
+		sm.locks[address] = l
+	}
+	return l
+}
+
+// SignBuildBroadcast signs and broadcasts msg on behalf of address using the
+// next cached sequence, auto-incrementing on success and refetching from the
+// chain whenever the cached value turns out to be stale.
+func (sm *SequenceManager) SignBuildBroadcast(ctx context.Context, address, privKeyHex string, msg model.Msg, memo string, mode BroadcastMode, fetchSeq func(ctx context.Context, address string) (int64, error)) (*BroadcastResult, error) {
+	lock := sm.lockFor(address)
+	lock.Lock()
+	defer lock.Unlock()
+
+	seq, ok := sm.nextSeq[address]
+	if !ok {
+		fetched, err := fetchSeq(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		seq = fetched
+	}
+
+	res, err := sm.transport.SignBuildBroadcastMode(ctx, msg, privKeyHex, seq, memo, mode)
+	if err != nil {
+		if errors.IsInvalidSequenceError(err) {
+			fetched, ferr := fetchSeq(ctx, address)
+			if ferr != nil {
+				return nil, ferr
+			}
+			sm.nextSeq[address] = fetched
+		}
+		return nil, err
+	}
+
+	// BroadcastCommit mode can return (res, nil) with Ok=false when CheckTx
+	// rejects the tx in the mempool, in which case the sequence was never
+	// actually consumed; only advance it once the tx is confirmed accepted.
+	if res.Ok {
+		sm.nextSeq[address] = seq + 1
+	}
+	return res, nil
+}
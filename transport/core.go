@@ -20,13 +20,11 @@ import (
 
 // Transport is a wrapper of tendermint rpc client and codec.
 type Transport struct {
-	chainId         string
-	nodeUrl         string
-	client          rpcclient.Client
-	Cdc             *wire.Codec
-	queryTimeout    time.Duration
-	BroadcastOkChan chan bool
-	QueryOkChan     chan bool
+	chainId      string
+	nodeUrl      string
+	client       rpcclient.Client
+	Cdc          *wire.Codec
+	queryTimeout time.Duration
 }
 
 // NewTransportFromConfig initiates an instance of Transport from config files.
@@ -44,13 +42,11 @@ func NewTransportFromConfig(queryTimeout time.Duration) *Transport {
 	}
 	rpc := rpcclient.NewHTTP(nodeUrl, "/websocket")
 	return &Transport{
-		chainId:         v.GetString("chain_id"),
-		nodeUrl:         nodeUrl,
-		client:          rpc,
-		Cdc:             MakeCodec(),
-		queryTimeout:    queryTimeout,
-		BroadcastOkChan: make(chan bool),
-		QueryOkChan:     make(chan bool),
+		chainId:      v.GetString("chain_id"),
+		nodeUrl:      nodeUrl,
+		client:       rpc,
+		Cdc:          MakeCodec(),
+		queryTimeout: queryTimeout,
 	}
 }
 
@@ -61,80 +57,96 @@ func NewTransportFromArgs(chainID, nodeUrl string, queryTimeout time.Duration) *
 	}
 	rpc := rpcclient.NewHTTP(nodeUrl, "/websocket")
 	return &Transport{
-		chainId:         chainID,
-		nodeUrl:         nodeUrl,
-		client:          rpc,
-		Cdc:             MakeCodec(),
-		queryTimeout:    queryTimeout,
-		BroadcastOkChan: make(chan bool),
-		QueryOkChan:     make(chan bool),
+		chainId:      chainID,
+		nodeUrl:      nodeUrl,
+		client:       rpc,
+		Cdc:          MakeCodec(),
+		queryTimeout: queryTimeout,
 	}
 }
 
-// Query from Tendermint with the provided key and storename
-func (t Transport) Query(key cmn.HexBytes, storeName string) (res []byte, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), t.queryTimeout)
+// QueryContext queries Tendermint with the provided key and storename.
+func (t Transport) QueryContext(ctx context.Context, key cmn.HexBytes, storeName string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.queryTimeout)
 	defer cancel()
+	return t.queryCtx(ctx, key, storeName, "key", 0)
+}
 
-	go func() {
-		res, err = t.query(key, storeName, "key", 0)
-	}()
+// Query from Tendermint with the provided key and storename.
+// Deprecated: use QueryContext, which takes a context.Context.
+func (t Transport) Query(key cmn.HexBytes, storeName string) ([]byte, error) {
+	return t.QueryContext(context.Background(), key, storeName)
+}
 
-	select {
-	case <-t.QueryOkChan:
-		break
-	case <-ctx.Done():
-		return nil, errors.Timeout("query timeout").AddCause(ctx.Err())
-	}
+// QueryAtHeightContext queries Tendermint with the provided key and storename
+// at a certain height.
+func (t Transport) QueryAtHeightContext(ctx context.Context, key cmn.HexBytes, storeName string, height int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.queryTimeout)
+	defer cancel()
+	return t.queryCtx(ctx, key, storeName, "key", height)
+}
 
-	return res, err
+// QueryAtHeight queries Tendermint with the provided key and storename at a certain height.
+// Deprecated: use QueryAtHeightContext, which takes a context.Context.
+func (t Transport) QueryAtHeight(key cmn.HexBytes, storeName string, height int64) ([]byte, error) {
+	return t.QueryAtHeightContext(context.Background(), key, storeName, height)
 }
 
-// Query from Tendermint with the provided key and storename at certain height
-func (t Transport) QueryAtHeight(key cmn.HexBytes, storeName string, height int64) (res []byte, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), t.queryTimeout)
+// QuerySubspaceContext queries Tendermint with the provided subspace and storename.
+func (t Transport) QuerySubspaceContext(ctx context.Context, subspace []byte, storeName string) (res []sdk.KVPair, err error) {
+	ctx, cancel := context.WithTimeout(ctx, t.queryTimeout)
 	defer cancel()
 
-	go func() {
-		res, err = t.query(key, storeName, "key", height)
-	}()
-
-	select {
-	case <-t.QueryOkChan:
-		break
-	case <-ctx.Done():
-		return nil, errors.Timeoutf("query at height %v timeout", height).AddCause(ctx.Err())
+	resRaw, err := t.queryCtx(ctx, subspace, storeName, "subspace", 0)
+	if err != nil {
+		return nil, err
 	}
 
-	return res, err
+	t.Cdc.UnmarshalJSON(resRaw, &res)
+	return res, nil
 }
 
-// Query from Tendermint with the provided subspace and storename
-func (t Transport) QuerySubspace(subspace []byte, storeName string) (res []sdk.KVPair, err error) {
-	ctx, cancel := context.WithTimeout(context.Background(), t.queryTimeout)
-	defer cancel()
-
-	var resRaw []byte
-	go func() {
-		resRaw, err = t.query(subspace, storeName, "subspace", 0)
-	}()
+// QuerySubspace queries Tendermint with the provided subspace and storename.
+// Deprecated: use QuerySubspaceContext, which takes a context.Context.
+func (t Transport) QuerySubspace(subspace []byte, storeName string) ([]sdk.KVPair, error) {
+	return t.QuerySubspaceContext(context.Background(), subspace, storeName)
+}
 
-	select {
-	case <-t.QueryOkChan:
-		break
-	case <-ctx.Done():
-		return nil, errors.Timeout("query subspace timeout").AddCause(ctx.Err())
+// QueryWithRetry wraps QueryContext and retries on transient RPC errors with
+// exponential backoff, bailing out early if ctx is cancelled.
+func (t Transport) QueryWithRetry(ctx context.Context, key cmn.HexBytes, storeName string, maxRetries int, initialBackoff time.Duration) (res []byte, err error) {
+	backoff := initialBackoff
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		res, err = t.QueryContext(ctx, key, storeName)
+		if err == nil || !isTransientQueryErr(err) {
+			return res, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Timeout("query with retry cancelled").AddCause(ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
 	}
+	return res, err
+}
 
-	if err != nil {
-		return nil, err
+// isTransientQueryErr reports whether err is worth retrying, as opposed to
+// an application-level failure (e.g. key not found) that would just repeat.
+func isTransientQueryErr(err error) bool {
+	switch err.(type) {
+	case *errors.Error:
+		return false
+	default:
+		return true
 	}
-
-	t.Cdc.UnmarshalJSON(resRaw, &res)
-	return
 }
 
-func (t Transport) query(key cmn.HexBytes, storeName, endPath string, height int64) (res []byte, err error) {
+// queryCtx performs the ABCI query and returns ctx.Err() as soon as ctx is
+// done. The underlying RPC call takes no context and is not cancelled: it
+// runs to completion in its own goroutine and its result is discarded.
+func (t Transport) queryCtx(ctx context.Context, key cmn.HexBytes, storeName, endPath string, height int64) (res []byte, err error) {
 	path := fmt.Sprintf("/store/%s/%s", storeName, endPath)
 	node, err := t.GetNode()
 	if err != nil {
@@ -145,23 +157,36 @@ func (t Transport) query(key cmn.HexBytes, storeName, endPath string, height int
 		Height:  height,
 		Trusted: true,
 	}
-	result, err := node.ABCIQueryWithOptions(path, key, opts)
-	if err != nil {
-		return res, err
-	}
 
-	resp := result.Response
-	if resp.Code != uint32(0) {
-		return res, errors.QueryFail("Query failed").AddBlockChainCode(resp.Code).AddBlockChainLog(resp.Log)
+	type queryResult struct {
+		result *ctypes.ResultABCIQuery
+		err    error
 	}
+	resultChan := make(chan queryResult, 1)
+	go func() {
+		result, err := node.ABCIQueryWithOptions(path, key, opts)
+		resultChan <- queryResult{result, err}
+	}()
 
-	if resp.Value == nil || len(resp.Value) == 0 {
-		return nil, errors.EmptyResponse("Empty response!")
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case qr := <-resultChan:
+		if qr.err != nil {
+			return nil, qr.err
+		}
+
+		resp := qr.result.Response
+		if resp.Code != uint32(0) {
+			return res, errors.QueryFail("Query failed").AddBlockChainCode(resp.Code).AddBlockChainLog(resp.Log)
+		}
+
+		if resp.Value == nil || len(resp.Value) == 0 {
+			return nil, errors.EmptyResponse("Empty response!")
+		}
+
+		return resp.Value, nil
 	}
-
-	t.QueryOkChan <- true
-
-	return resp.Value, nil
 }
 
 // QueryBlock queries a block with a certain height from blockchain.
@@ -184,26 +209,40 @@ func (t Transport) QueryBlockStatus() (res *ctypes.ResultStatus, err error) {
 	return node.Status()
 }
 
-// BroadcastTx broadcasts a transcation to blockchain.
-func (t Transport) BroadcastTx(tx []byte) (*ctypes.ResultBroadcastTxCommit, error) {
+// BroadcastTxContext broadcasts a transcation to blockchain.
+func (t Transport) BroadcastTxContext(ctx context.Context, tx []byte) (res *ctypes.ResultBroadcastTxCommit, err error) {
 	node, err := t.GetNode()
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := node.BroadcastTxCommit(tx)
-	if err != nil {
-		return nil, err
+	type broadcastResult struct {
+		res *ctypes.ResultBroadcastTxCommit
+		err error
 	}
+	resultChan := make(chan broadcastResult, 1)
+	go func() {
+		res, err := node.BroadcastTxCommit(tx)
+		resultChan <- broadcastResult{res, err}
+	}()
 
-	t.BroadcastOkChan <- true
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case br := <-resultChan:
+		return br.res, br.err
+	}
+}
 
-	return res, nil
+// BroadcastTx broadcasts a transcation to blockchain.
+// Deprecated: use BroadcastTxContext, which takes a context.Context.
+func (t Transport) BroadcastTx(tx []byte) (*ctypes.ResultBroadcastTxCommit, error) {
+	return t.BroadcastTxContext(context.Background(), tx)
 }
 
-// SignBuildBroadcast signs msg with private key and then broadcasts
+// SignBuildBroadcastContext signs msg with private key and then broadcasts
 // the transaction to blockchain.
-func (t Transport) SignBuildBroadcast(msg model.Msg,
+func (t Transport) SignBuildBroadcastContext(ctx context.Context, msg model.Msg,
 	privKeyHex string, seq int64, memo string) (*ctypes.ResultBroadcastTxCommit, error) {
 	msgs := []model.Msg{msg}
 
@@ -229,7 +268,14 @@ func (t Transport) SignBuildBroadcast(msg model.Msg,
 	}
 
 	// broadcast
-	return t.BroadcastTx(txByte)
+	return t.BroadcastTxContext(ctx, txByte)
+}
+
+// SignBuildBroadcast signs msg with private key and then broadcasts
+// the transaction to blockchain.
+// Deprecated: use SignBuildBroadcastContext, which takes a context.Context.
+func (t Transport) SignBuildBroadcast(msg model.Msg, privKeyHex string, seq int64, memo string) (*ctypes.ResultBroadcastTxCommit, error) {
+	return t.SignBuildBroadcastContext(context.Background(), msg, privKeyHex, seq, memo)
 }
 
 // GetNote returns the Tendermint rpc client node.
@@ -0,0 +1,154 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+
+	"github.com/lino-network/lino-go/errors"
+	"github.com/lino-network/lino-go/model"
+)
+
+const (
+	// eventBufferSize is the capacity of the per-subscription channel
+	// handed back to callers, so a slow consumer doesn't stall the dispatch loop.
+	eventBufferSize = 64
+
+	// reconnectInitialBackoff is the starting delay before retrying a dropped
+	// websocket connection; it doubles on each consecutive failure.
+	reconnectInitialBackoff = 500 * time.Millisecond
+	reconnectMaxBackoff     = 30 * time.Second
+)
+
+// EventStream subscribes to Tendermint events over the websocket endpoint
+// already opened by the underlying Transport's rpc client, and dispatches
+// them to typed Go channels.
+type EventStream struct {
+	transport *Transport
+}
+
+// NewEventStream wraps t with a subscription API.
+func NewEventStream(t *Transport) *EventStream {
+	return &EventStream{transport: t}
+}
+
+// SubscribeNewBlocks streams every new block committed to the chain.
+func (es *EventStream) SubscribeNewBlocks(ctx context.Context) (<-chan *ctypes.ResultEvent, error) {
+	return es.subscribe(ctx, "event-stream-new-block", types.QueryForEvent(types.EventNewBlock).String())
+}
+
+// SubscribeTx streams transactions matching the given Tendermint event query,
+// e.g. "tm.event='Tx' AND transfer.sender='cosmos1...'".
+func (es *EventStream) SubscribeTx(ctx context.Context, query string) (<-chan *ctypes.ResultEvent, error) {
+	return es.subscribe(ctx, "event-stream-tx", query)
+}
+
+// SubscribeValidatorSetUpdates streams validator set change events.
+func (es *EventStream) SubscribeValidatorSetUpdates(ctx context.Context) (<-chan *ctypes.ResultEvent, error) {
+	return es.subscribe(ctx, "event-stream-vals", types.QueryForEvent(types.EventValidatorSetUpdates).String())
+}
+
+// subscribe starts (or reuses) the underlying rpc client, subscribes with
+// the given query, and relays events onto a typed channel until ctx is
+// cancelled, reconnecting with backoff if the websocket drops.
+func (es *EventStream) subscribe(ctx context.Context, subscriber, query string) (<-chan *ctypes.ResultEvent, error) {
+	node, err := es.transport.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	wsClient, ok := node.(rpcclient.EventsClient)
+	if !ok {
+		return nil, errors.InvalidNodeURL("node does not support event subscriptions")
+	}
+
+	if starter, ok := node.(interface{ Start() error }); ok {
+		// Start is a no-op if already running; ignore the "already started" error.
+		_ = starter.Start()
+	}
+
+	out := make(chan *ctypes.ResultEvent, eventBufferSize)
+
+	go es.dispatchLoop(ctx, wsClient, subscriber, query, out)
+
+	return out, nil
+}
+
+func (es *EventStream) dispatchLoop(ctx context.Context, wsClient rpcclient.EventsClient, subscriber, query string, out chan<- *ctypes.ResultEvent) {
+	defer close(out)
+
+	backoff := reconnectInitialBackoff
+	for {
+		resultChan, err := wsClient.Subscribe(ctx, subscriber, query)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > reconnectMaxBackoff {
+				backoff = reconnectMaxBackoff
+			}
+			continue
+		}
+		backoff = reconnectInitialBackoff
+
+		es.relay(ctx, resultChan, out)
+
+		_ = wsClient.Unsubscribe(context.Background(), subscriber, query)
+
+		if ctx.Err() != nil {
+			return
+		}
+		// The websocket dropped; loop around and resubscribe.
+	}
+}
+
+// DecodeTxMsgs decodes a tx event's raw bytes into the Lino messages it
+// carries, so callers building an indexer or notifier don't need to know
+// about the underlying amino tx envelope.
+func (es *EventStream) DecodeTxMsgs(evt *ctypes.ResultEvent) ([]model.Msg, error) {
+	txData, ok := evt.Data.(types.EventDataTx)
+	if !ok {
+		return nil, errors.InvalidArg("event does not carry tx data")
+	}
+
+	var tx auth.StdTx
+	if err := es.transport.Cdc.UnmarshalBinaryLengthPrefixed(txData.Tx, &tx); err != nil {
+		return nil, err
+	}
+
+	msgs := make([]model.Msg, len(tx.Msgs))
+	for i, m := range tx.Msgs {
+		msg, ok := m.(model.Msg)
+		if !ok {
+			return nil, errors.InvalidArg("decoded tx carries a message that is not a model.Msg")
+		}
+		msgs[i] = msg
+	}
+	return msgs, nil
+}
+
+// relay copies events from resultChan to out until the source channel
+// closes (connection dropped) or ctx is cancelled.
+func (es *EventStream) relay(ctx context.Context, resultChan <-chan ctypes.ResultEvent, out chan<- *ctypes.ResultEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-resultChan:
+			if !ok {
+				return
+			}
+			select {
+			case out <- &evt:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
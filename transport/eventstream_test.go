@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// testMsg is a minimal model.Msg implementation used only to round-trip
+// through the amino tx envelope in TestDecodeTxMsgs.
+type testMsg struct {
+	From string
+}
+
+func (m testMsg) Route() string         { return "test" }
+func (m testMsg) Type() string          { return "test_msg" }
+func (m testMsg) ValidateBasic() sdk.Error {
+	return nil
+}
+func (m testMsg) GetSignBytes() []byte { return []byte(m.From) }
+func (m testMsg) GetSigners() []sdk.AccAddress {
+	return nil
+}
+
+func TestDecodeTxMsgs(t *testing.T) {
+	transport := NewTransportFromArgs("test-chain", "", 0)
+	transport.Cdc.RegisterConcrete(testMsg{}, "lino-go/testMsg", nil)
+
+	msg := testMsg{From: "lino-user"}
+	tx := auth.StdTx{Msgs: []sdk.Msg{msg}}
+
+	txBytes, err := transport.Cdc.MarshalBinaryLengthPrefixed(tx)
+	if err != nil {
+		t.Fatalf("failed to encode tx: %v", err)
+	}
+
+	es := NewEventStream(transport)
+	evt := &ctypes.ResultEvent{
+		Data: types.EventDataTx{TxResult: types.TxResult{Tx: txBytes}},
+	}
+
+	msgs, err := es.DecodeTxMsgs(evt)
+	if err != nil {
+		t.Fatalf("DecodeTxMsgs: unexpected err %v", err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 msg, got %d", len(msgs))
+	}
+
+	got, ok := msgs[0].(testMsg)
+	if !ok {
+		t.Fatalf("decoded msg has wrong type: %T", msgs[0])
+	}
+	if got.From != msg.From {
+		t.Errorf("got %q, want %q", got.From, msg.From)
+	}
+}
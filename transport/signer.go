@@ -0,0 +1,232 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/btcsuite/btcd/btcec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkbech32 "github.com/cosmos/cosmos-sdk/types/bech32"
+	ledgercosmos "github.com/cosmos/ledger-cosmos-go"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"github.com/lino-network/lino-go/errors"
+	"github.com/lino-network/lino-go/model"
+)
+
+// Signer abstracts over where a private key lives, so Transport never has to
+// hold raw key material in process. The signer from NewHexKeySigner keeps the
+// old in-process behavior; those from NewLedgerSigner and NewRemoteSigner
+// keep custody off this machine.
+type Signer interface {
+	// PubKey returns the signer's public key.
+	PubKey() crypto.PubKey
+	// Sign returns the signature over signBytes.
+	Sign(ctx context.Context, signBytes []byte) ([]byte, error)
+	// Address returns the signer's account address.
+	Address() sdk.AccAddress
+}
+
+// hexKeySigner signs in-process with a raw hex-encoded private key; this is
+// the transport's original behavior, now expressed as a Signer.
+type hexKeySigner struct {
+	privKey crypto.PrivKey
+}
+
+// NewHexKeySigner builds a Signer from a hex-encoded private key.
+func NewHexKeySigner(privKeyHex string) (Signer, error) {
+	privKey, err := GetPrivKeyFromHex(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	return &hexKeySigner{privKey: privKey}, nil
+}
+
+func (s *hexKeySigner) PubKey() crypto.PubKey {
+	return s.privKey.PubKey()
+}
+
+func (s *hexKeySigner) Sign(ctx context.Context, signBytes []byte) ([]byte, error) {
+	return s.privKey.Sign(signBytes)
+}
+
+func (s *hexKeySigner) Address() sdk.AccAddress {
+	return sdk.AccAddress(s.privKey.PubKey().Address())
+}
+
+// ledgerSigner signs via a Ledger hardware wallet running the Cosmos app,
+// with the key derived at path; hrp is the Lino HRP used by Bech32Address.
+type ledgerSigner struct {
+	hrp    string
+	path   []uint32
+	device ledgerDevice
+	pubKey secp256k1.PubKeySecp256k1
+}
+
+// ledgerDevice is the subset of cosmos-sdk's Ledger integration this signer
+// needs; it's an interface so tests can substitute a fake device.
+type ledgerDevice interface {
+	GetPublicKeySecp256k1(path []uint32) ([]byte, error)
+	SignSecp256k1(path []uint32, signBytes []byte) ([]byte, error)
+}
+
+// NewLedgerSigner opens a connection to a Ledger device and builds a Signer
+// that derives its key at path. hrp (the Lino bech32 human-readable part) does
+// not affect derivation; it is only used to encode the address.
+func NewLedgerSigner(hrp string, path []uint32) (Signer, error) {
+	device, err := openLedgerDevice()
+	if err != nil {
+		return nil, errors.InvalidArg("failed to open ledger device").AddCause(err)
+	}
+
+	pubKeyBytes, err := device.GetPublicKeySecp256k1(path)
+	if err != nil {
+		return nil, errors.InvalidArg("failed to read ledger public key").AddCause(err)
+	}
+
+	// GetPublicKeySecp256k1 returns the uncompressed (65-byte) key; compress
+	// it before copying into the fixed 33-byte PubKeySecp256k1 array, or the
+	// result is silently truncated into a wrong/invalid key.
+	parsedKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, errors.InvalidArg("failed to parse ledger public key").AddCause(err)
+	}
+	var pubKey secp256k1.PubKeySecp256k1
+	copy(pubKey[:], parsedKey.SerializeCompressed())
+
+	return &ledgerSigner{hrp: hrp, path: path, device: device, pubKey: pubKey}, nil
+}
+
+func (s *ledgerSigner) PubKey() crypto.PubKey {
+	return s.pubKey
+}
+
+func (s *ledgerSigner) Sign(ctx context.Context, signBytes []byte) ([]byte, error) {
+	return s.device.SignSecp256k1(s.path, signBytes)
+}
+
+func (s *ledgerSigner) Address() sdk.AccAddress {
+	return sdk.AccAddress(s.pubKey.Address())
+}
+
+// Bech32Address returns the signer's address encoded under the Lino HRP
+// passed to NewLedgerSigner, instead of whatever prefix sdk.AccAddress.String
+// would apply from the process-global bech32 config.
+func (s *ledgerSigner) Bech32Address() (string, error) {
+	return sdkbech32.ConvertAndEncode(s.hrp, s.pubKey.Address())
+}
+
+// ledgerDeviceImpl adapts the real ledger-cosmos-go app connection to the
+// ledgerDevice interface.
+type ledgerDeviceImpl struct {
+	app *ledgercosmos.LedgerCosmos
+}
+
+func (d *ledgerDeviceImpl) GetPublicKeySecp256k1(path []uint32) ([]byte, error) {
+	return d.app.GetPublicKeySECP256K1(path)
+}
+
+func (d *ledgerDeviceImpl) SignSecp256k1(path []uint32, signBytes []byte) ([]byte, error) {
+	return d.app.SignSECP256K1(path, signBytes)
+}
+
+// openLedgerDevice connects to the first Ledger device running the Cosmos app.
+func openLedgerDevice() (ledgerDevice, error) {
+	app, err := ledgercosmos.FindLedgerCosmosUserApp()
+	if err != nil {
+		return nil, err
+	}
+	return &ledgerDeviceImpl{app: app}, nil
+}
+
+// remoteSigner delegates signing to an external KMS over HTTPS, POSTing the
+// canonical sign-bytes and expecting back a raw signature.
+type remoteSigner struct {
+	url     string
+	pubKey  crypto.PubKey
+	address sdk.AccAddress
+	client  *http.Client
+}
+
+// NewRemoteSigner builds a Signer that POSTs sign-bytes to url and returns
+// the signature in the response body. pubKey/address must be fetched out of
+// band (e.g. from the KMS's key-listing endpoint) since a remote signer has
+// no local key material to derive them from.
+func NewRemoteSigner(url string, pubKey crypto.PubKey, tlsConfig *tls.Config) Signer {
+	return &remoteSigner{
+		url:     url,
+		pubKey:  pubKey,
+		address: sdk.AccAddress(pubKey.Address()),
+		client:  &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+	}
+}
+
+func (s *remoteSigner) PubKey() crypto.PubKey {
+	return s.pubKey
+}
+
+func (s *remoteSigner) Address() sdk.AccAddress {
+	return s.address
+}
+
+func (s *remoteSigner) Sign(ctx context.Context, signBytes []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(signBytes))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.QueryFail("remote signer returned non-200 status").AddBlockChainCode(uint32(resp.StatusCode))
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// SignBuildBroadcastWithSigner signs msg using signer instead of a raw
+// in-process private key, then broadcasts it using mode.
+func (t Transport) SignBuildBroadcastWithSigner(ctx context.Context, msg model.Msg,
+	signer Signer, seq int64, memo string, mode BroadcastMode) (*BroadcastResult, error) {
+	msgs := []model.Msg{msg}
+
+	signMsgBytes, err := EncodeSignMsg(t.Cdc, msgs, t.chainId, seq)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := signer.Sign(ctx, signMsgBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	txByte, err := EncodeTx(t.Cdc, msgs, signer.PubKey(), sig, seq, memo)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.BroadcastTxMode(ctx, txByte, mode)
+}
+
+// SignBuildBroadcastHexKey signs msg with a raw hex private key and
+// broadcasts it. It's a convenience wrapper around
+// SignBuildBroadcastWithSigner for callers that don't need a Ledger or
+// remote signer.
+func (t Transport) SignBuildBroadcastHexKey(ctx context.Context, msg model.Msg,
+	privKeyHex string, seq int64, memo string, mode BroadcastMode) (*BroadcastResult, error) {
+	signer, err := NewHexKeySigner(privKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	return t.SignBuildBroadcastWithSigner(ctx, msg, signer, seq, memo, mode)
+}
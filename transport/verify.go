@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	rootmulti "github.com/cosmos/cosmos-sdk/store/rootmulti"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	tmmath "github.com/tendermint/tendermint/libs/math"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/lino-network/lino-go/errors"
+)
+
+// VerifyingTransport wraps a base Transport and validates every query
+// against a trusted validator set and Merkle-proves the returned value
+// against the block's AppHash, instead of trusting a single full node.
+type VerifyingTransport struct {
+	base *Transport
+
+	// trustPeriod bounds how stale trustedHeader is allowed to get; once
+	// it's older than trustPeriod we refuse to verify anything further,
+	// since a node could have equivocated undetected in the meantime.
+	trustPeriod time.Duration
+
+	mu            sync.RWMutex
+	trustedHeader *tmtypes.SignedHeader
+	trustedVals   *tmtypes.ValidatorSet
+}
+
+// NewVerifyingTransport builds a VerifyingTransport rooted at trustedHeader,
+// verified against trustedVals. trustPeriod bounds how stale the trusted
+// header is allowed to get; once it is older than that, every query fails
+// and the trust anchor is not updated.
+func NewVerifyingTransport(base *Transport, trustedHeader *tmtypes.SignedHeader, trustedVals *tmtypes.ValidatorSet, trustPeriod time.Duration) *VerifyingTransport {
+	return &VerifyingTransport{
+		base:          base,
+		trustPeriod:   trustPeriod,
+		trustedHeader: trustedHeader,
+		trustedVals:   trustedVals,
+	}
+}
+
+// Query performs a Merkle-proved, header-verified query, refusing to trust
+// the responding node's own claim that the value is correct.
+func (vt *VerifyingTransport) Query(ctx context.Context, key []byte, storeName string) ([]byte, error) {
+	node, err := vt.base.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := node.ABCIQueryWithOptions("/store/"+storeName+"/key", key, rpcclient.ABCIQueryOptions{Height: 0, Trusted: false})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := result.Response
+	if resp.Code != uint32(0) {
+		return nil, errors.QueryFail("verifying query failed").AddBlockChainCode(resp.Code).AddBlockChainLog(resp.Log)
+	}
+
+	header, err := vt.verifiedHeaderAt(ctx, resp.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPath := merkleKeyPath(storeName, key)
+	prover := rootmulti.DefaultProofRuntime()
+	if err := prover.VerifyValue(resp.Proof, header.AppHash, keyPath, resp.Value); err != nil {
+		return nil, errors.QueryFail("merkle proof verification failed").AddCause(err)
+	}
+
+	return resp.Value, nil
+}
+
+// merkleKeyPath builds the escaped "/<store>/<key>" path rootmulti's proof
+// runtime expects, instead of naively string-concatenating binary key bytes.
+func merkleKeyPath(storeName string, key []byte) string {
+	path := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL)
+	return path.String()
+}
+
+// verifiedHeaderAt returns a header for height that has been checked against
+// the trusted validator set, moving the trust anchor forward (and its
+// validator set along with it) whenever height is newer than what's
+// currently trusted. The move is a single step straight to height; no
+// intermediate heights are fetched, so if the trusted set backs less than
+// trustLevel of that commit the call fails instead of bisecting. It refuses
+// to serve anything once the trust anchor has
+// aged past trustPeriod, and refuses heights older than the anchor, since
+// verifying those would require the validator set that was in effect back
+// then rather than the one currently trusted.
+func (vt *VerifyingTransport) verifiedHeaderAt(ctx context.Context, height int64) (*tmtypes.Header, error) {
+	vt.mu.RLock()
+	trusted := vt.trustedHeader
+	vals := vt.trustedVals
+	vt.mu.RUnlock()
+
+	if time.Since(trusted.Time) > vt.trustPeriod {
+		return nil, errors.QueryFail("trusted header has expired; provide a fresh trust anchor")
+	}
+
+	if height == trusted.Height {
+		return &trusted.Header, nil
+	}
+	if height < trusted.Height {
+		return nil, errors.QueryFail("cannot verify a height older than the trust anchor")
+	}
+
+	node, err := vt.base.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := node.Commit(&height)
+	if err != nil {
+		return nil, err
+	}
+
+	signedHeader := &commit.SignedHeader
+	if err := signedHeader.ValidateBasic(vt.base.chainId); err != nil {
+		return nil, errors.QueryFail("invalid header from node").AddCause(err)
+	}
+
+	// First, a cheap trusting check: the old set must back enough of the new
+	// commit's voting power to be worth fetching the full new validator set.
+	if err := vals.VerifyCommitTrusting(vt.base.chainId, commit.Commit.BlockID, signedHeader.Height, commit.Commit, trustLevel); err != nil {
+		return nil, errors.QueryFail("validator signature verification failed").AddCause(err)
+	}
+
+	valsResult, err := node.Validators(&height)
+	if err != nil {
+		return nil, errors.QueryFail("failed to fetch validator set at new trust height").AddCause(err)
+	}
+	newVals := tmtypes.NewValidatorSet(valsResult.Validators)
+
+	// The fetched set must be the exact one the header committed to, not
+	// merely whatever the (possibly malicious) node chose to hand back.
+	if !bytes.Equal(newVals.Hash(), signedHeader.ValidatorsHash) {
+		return nil, errors.QueryFail("fetched validator set does not match header's validators hash")
+	}
+
+	// Now do the full +2/3 check against that exact set before trusting it
+	// as the new anchor; VerifyCommitTrusting alone only proves 1/3 overlap
+	// with the *old* set, which isn't enough to promote a new one.
+	if err := newVals.VerifyCommit(vt.base.chainId, commit.Commit.BlockID, signedHeader.Height, commit.Commit); err != nil {
+		return nil, errors.QueryFail("full validator commit verification failed").AddCause(err)
+	}
+
+	vt.mu.Lock()
+	vt.trustedHeader = signedHeader
+	vt.trustedVals = newVals
+	vt.mu.Unlock()
+
+	return &signedHeader.Header, nil
+}
+
+// trustLevel is the minimum fraction of the *trusted* validator set's voting
+// power that must back a commit signed by the (possibly different) next
+// validator set for it to be accepted, per the Tendermint light client spec.
+var trustLevel = tmmath.Fraction{Numerator: 1, Denominator: 3}